@@ -0,0 +1,20 @@
+package offchain
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+// cidV1FromSHA256 builds a CIDv1 (raw codec) from a digest already computed
+// by hashAndCID, so the CID comes from the same hash recorded in the
+// Attachment rather than from hashing the payload twice. LocalStore records
+// this CID; IPFSStore records the CID the IPFS node reports instead.
+func cidV1FromSHA256(digest []byte) (cid.Cid, error) {
+	mh, err := multihash.Encode(digest, multihash.SHA2_256)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to encode multihash: %w", err)
+	}
+	return cid.NewCidV1(cid.Raw, mh), nil
+}
@@ -0,0 +1,56 @@
+package offchain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	shell "github.com/ipfs/go-ipfs-api"
+)
+
+// IPFSStore uploads attachments to an IPFS node (or pinning service) via its
+// HTTP API, giving every org a content-addressed way to fetch the same bytes
+// without trusting a single org's server.
+type IPFSStore struct {
+	sh *shell.Shell
+}
+
+// NewIPFSStore connects to the IPFS HTTP API at apiURL (e.g.
+// "localhost:5001").
+func NewIPFSStore(apiURL string) *IPFSStore {
+	return &IPFSStore{sh: shell.NewShell(apiURL)}
+}
+
+func (s *IPFSStore) Put(kind, mimeType string, size int64, r io.Reader) (Attachment, error) {
+	var buf bytes.Buffer
+	sha256Hex, _, err := hashAndCID(&buf, r)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	// RawLeaves keeps single-block uploads as a bare raw-codec CID; without it
+	// anything past IPFS's default chunk size (256 KiB) comes back wrapped in
+	// a multi-block dag-pb UnixFS node, which never equals a locally-derived
+	// raw CID. Trust whatever CID IPFS reports rather than re-deriving one.
+	addedCID, err := s.sh.Add(bytes.NewReader(buf.Bytes()), shell.CidVersion(1), shell.RawLeaves(true))
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to add to IPFS: %w", err)
+	}
+
+	return Attachment{
+		Kind:      kind,
+		CID:       addedCID,
+		SHA256:    sha256Hex,
+		MimeType:  mimeType,
+		SizeBytes: size,
+		URI:       "ipfs://" + addedCID,
+	}, nil
+}
+
+func (s *IPFSStore) Get(uri string) (io.ReadCloser, error) {
+	cidStr := uri
+	if len(uri) > 7 && uri[:7] == "ipfs://" {
+		cidStr = uri[7:]
+	}
+	return s.sh.Cat(cidStr)
+}
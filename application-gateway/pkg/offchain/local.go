@@ -0,0 +1,76 @@
+package offchain
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore persists attachments on the local filesystem, named by their
+// CID, and serves them back over HTTP via BaseURL. Suitable for a single
+// backing server or a shared volume mounted by one; for anything that needs
+// to be fetched by peers across orgs, use IPFSStore instead.
+type LocalStore struct {
+	// Dir is the directory attachments are written to. It must already exist.
+	Dir string
+	// BaseURL is prefixed to a file's CID to build the Attachment.URI
+	// returned from Put, e.g. "https://docs.example.com/attachments".
+	BaseURL string
+}
+
+// NewLocalStore returns a LocalStore rooted at dir, serving files under
+// baseURL.
+func NewLocalStore(dir, baseURL string) *LocalStore {
+	return &LocalStore{Dir: dir, BaseURL: baseURL}
+}
+
+func (s *LocalStore) Put(kind, mimeType string, size int64, r io.Reader) (Attachment, error) {
+	tmp, err := os.CreateTemp(s.Dir, "upload-*")
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer tmp.Close()
+
+	sha256Hex, c, err := hashAndCID(tmp, r)
+	if err != nil {
+		os.Remove(tmp.Name())
+		return Attachment{}, err
+	}
+
+	finalPath := filepath.Join(s.Dir, c.String())
+	if err := os.Rename(tmp.Name(), finalPath); err != nil {
+		os.Remove(tmp.Name())
+		return Attachment{}, fmt.Errorf("failed to finalize upload: %w", err)
+	}
+
+	return Attachment{
+		Kind:      kind,
+		CID:       c.String(),
+		SHA256:    sha256Hex,
+		MimeType:  mimeType,
+		SizeBytes: size,
+		URI:       s.BaseURL + "/" + c.String(),
+	}, nil
+}
+
+func (s *LocalStore) Get(uri string) (io.ReadCloser, error) {
+	if !isHTTPURI(uri) {
+		return os.Open(filepath.Join(s.Dir, filepath.Base(uri)))
+	}
+
+	resp, err := http.Get(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", uri, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to fetch %s: status %s", uri, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+func isHTTPURI(uri string) bool {
+	return len(uri) > 7 && (uri[:7] == "http://" || (len(uri) > 8 && uri[:8] == "https://"))
+}
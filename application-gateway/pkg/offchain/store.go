@@ -0,0 +1,55 @@
+// Package offchain uploads large binary payloads (certification PDFs, lab
+// reports, product photos) outside the ledger and hands back a
+// content-addressed reference that's cheap to store on-chain.
+package offchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+)
+
+// Attachment mirrors the chaincode's Attachment struct. It's the value
+// CreateAsset/AttachDocument expect, already hashed and uploaded.
+type Attachment struct {
+	Kind      string `json:"kind"`
+	CID       string `json:"cid"`
+	SHA256    string `json:"sha256"`
+	MimeType  string `json:"mimeType"`
+	SizeBytes int64  `json:"sizeBytes"`
+	URI       string `json:"uri"`
+}
+
+// Store uploads a document and returns a tamper-evident reference to it.
+// Implementations must compute the CID and SHA-256 of the exact bytes they
+// stored, so a later VerifyAttachment call against the chaincode is
+// meaningful.
+type Store interface {
+	// Put uploads the contents of r (size bytes long, of the given MIME
+	// type and Attachment kind) and returns the resulting Attachment.
+	Put(kind, mimeType string, size int64, r io.Reader) (Attachment, error)
+
+	// Get fetches the bytes previously stored at uri.
+	Get(uri string) (io.ReadCloser, error)
+}
+
+// hashAndCID hashes r while also writing it to dst, returning the lowercase
+// hex-encoded SHA-256 digest and its equivalent CIDv1 (raw codec). Used by
+// Store implementations so both values reflect exactly what ends up
+// persisted, rather than being computed from separate reads of the payload.
+func hashAndCID(dst io.Writer, r io.Reader) (sha256Hex string, c cid.Cid, err error) {
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(dst, h), r); err != nil {
+		return "", cid.Undef, fmt.Errorf("failed to hash payload: %w", err)
+	}
+	digest := h.Sum(nil)
+
+	c, err = cidV1FromSHA256(digest)
+	if err != nil {
+		return "", cid.Undef, err
+	}
+	return hex.EncodeToString(digest), c, nil
+}
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/x509"
@@ -21,6 +22,8 @@ import (
 	"google.golang.org/grpc/credentials"
     "encoding/json"
     "google.golang.org/grpc/status"
+
+    "github.com/Meharab/meat/application-gateway/pkg/offchain"
 )
 
 func envOrDefault(key, def string) string {
@@ -45,8 +48,18 @@ var (
 	endorseTimeout  = 15 * time.Second
 	submitTimeout   = 5 * time.Second
 	commitTimeout   = 1 * time.Minute
+
+	attachmentDir     = envOrDefault("ATTACHMENT_DIR", "offchain-store")
+	attachmentBaseURL = envOrDefault("ATTACHMENT_BASE_URL", "https://docs.example.com/attachments")
 )
 
+// productImagePayload stands in for the product photo a real client would
+// read off disk or from a camera upload. Content-addressing it through
+// pkg/offchain and recording its CID/SHA-256 on-chain (via
+// AttachDocument) is what makes product_image tamper-evident instead of a
+// bare, swappable filename string.
+var productImagePayload = []byte("hilsa-fish-product-photo-placeholder")
+
 func main() {
 	displayInputParameters()
 
@@ -92,6 +105,10 @@ func main() {
 		log.Fatalf("*** CreateAsset failed: %v", err)
 	}
 
+	if err := attachProductImage(contract, productID); err != nil {
+		log.Fatalf("*** AttachDocument failed: %v", err)
+	}
+
 	if err := readAssetByID(contract, productID); err != nil {
 		log.Fatalf("*** ReadAsset failed: %v", err)
 	}
@@ -258,11 +275,9 @@ func createAsset(contract *client.Contract, productId string) error {
         "product_name_bn": "Frozen Hilsa Fish",
         "species_en": "Hilsa",
         "species_bn": "Hilsa",
-        "product_image": "hilsa.jpg",
         "date_of_harvesting": "2025-09-01",
         "date_of_packaging": "2025-09-03",
         "expired_date": "2026-03-01",
-        "mrp": 1200.5,
         "has_blast_freezer": true,
         "has_iqf": false,
         "has_vacuum_package": true,
@@ -274,7 +289,6 @@ func createAsset(contract *client.Contract, productId string) error {
         "has_freezer_van_transportation": true,
         "batch_number": "BATCH-001",
         "lot_number": "LOT-001",
-        "net_weight": 2.5,
         "certification_en": []string{"ISO22000", "HACCP"},
         "certification_bn": []string{"ISO22000", "HACCP"},
         "production_latitude": 23.8103,
@@ -296,8 +310,24 @@ func createAsset(contract *client.Contract, productId string) error {
         return fmt.Errorf("failed to marshal asset to JSON: %w", err)
     }
 
+    // Pricing-sensitive fields travel via the transient map, not the
+    // proposal payload, so they never land on the (channel-wide) ledger.
+    private := map[string]interface{}{
+        "mrp": 1200.5,
+        "net_weight": 2.5,
+        "secondary_batch": "SBATCH-001",
+    }
+    privatePayload, err := json.Marshal(private)
+    if err != nil {
+        return fmt.Errorf("failed to marshal private asset details to JSON: %w", err)
+    }
+
     log.Printf("\n--> Submit Transaction: CreateAsset (productId=%s)", productId)
-    _, err = contract.SubmitTransaction("CreateAsset", string(payload))
+    _, err = contract.Submit(
+        "CreateAsset",
+        client.WithArguments(string(payload)),
+        client.WithTransient(map[string][]byte{"asset_private": privatePayload}),
+    )
     if err != nil {
         if s, ok := status.FromError(err); ok {
             log.Printf("SubmitTransaction failed: code=%v message=%q", s.Code(), s.Message())
@@ -312,6 +342,35 @@ func createAsset(contract *client.Contract, productId string) error {
     return nil
 }
 
+// attachProductImage uploads the product photo through a content-addressed
+// Store and records the resulting CID/SHA-256 on-chain via AttachDocument,
+// so a client reading the asset back can use VerifyAttachment to confirm the
+// photo it fetched is the one that was actually recorded by AttachDocument.
+func attachProductImage(contract *client.Contract, productId string) error {
+    if err := os.MkdirAll(attachmentDir, 0o755); err != nil {
+        return fmt.Errorf("failed to create attachment store dir %s: %w", attachmentDir, err)
+    }
+    store := offchain.NewLocalStore(attachmentDir, attachmentBaseURL)
+
+    attachment, err := store.Put("product_image", "image/jpeg", int64(len(productImagePayload)), bytes.NewReader(productImagePayload))
+    if err != nil {
+        return fmt.Errorf("failed to upload product image: %w", err)
+    }
+
+    attachmentJSON, err := json.Marshal(attachment)
+    if err != nil {
+        return fmt.Errorf("failed to marshal attachment: %w", err)
+    }
+
+    log.Printf("\n--> Submit Transaction: AttachDocument (productId=%s, cid=%s)", productId, attachment.CID)
+    _, err = contract.SubmitTransaction("AttachDocument", productId, string(attachmentJSON))
+    if err != nil {
+        return fmt.Errorf("AttachDocument failed: %w", err)
+    }
+    log.Printf("*** AttachDocument transaction committed successfully (productId=%s)", productId)
+    return nil
+}
+
 
 func readAssetByID(contract *client.Contract, productId string) error {
 	log.Printf("\n--> Evaluate Transaction: ReadAsset (productId=%s)", productId)
@@ -0,0 +1,58 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// AttachDocument records a reference to an off-chain document against
+// productID. The caller is responsible for uploading the document and
+// computing its CID/SHA-256 beforehand (see the client's pkg/offchain
+// package); only the Attachment reference (CID, hash and metadata) is stored
+// on-chain, never the document itself.
+func (s *SmartContract) AttachDocument(ctx contractapi.TransactionContextInterface, productID, attachmentJSON string) error {
+	var attachment Attachment
+	if err := json.Unmarshal([]byte(attachmentJSON), &attachment); err != nil {
+		return fmt.Errorf("failed to unmarshal attachment: %v", err)
+	}
+	if attachment.CID == "" || attachment.SHA256 == "" {
+		return fmt.Errorf("attachment must have both a CID and a SHA256 hash")
+	}
+
+	asset, err := s.ReadAsset(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	asset.Attachments = append(asset.Attachments, attachment)
+
+	assetBytes, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+	key := fmt.Sprintf("QR:%s", productID)
+	if err := ctx.GetStub().PutState(key, assetBytes); err != nil {
+		return fmt.Errorf("failed to put to world state: %v", err)
+	}
+
+	return nil
+}
+
+// VerifyAttachment reports whether productID has an attachment matching both
+// cid and sha256, so a client can confirm a fetched document hasn't been
+// swapped since it was recorded.
+func (s *SmartContract) VerifyAttachment(ctx contractapi.TransactionContextInterface, productID, cid, sha256 string) (bool, error) {
+	asset, err := s.ReadAsset(ctx, productID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, attachment := range asset.Attachments {
+		if attachment.CID == cid && attachment.SHA256 == sha256 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,75 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+const (
+	// pricingCollection holds MRP, weight and batch detail visible to the
+	// producer org and the buyer org. Defined in collections_config.json.
+	pricingCollection = "pricingCollection"
+	// regulatorCollection holds the same pricing detail visible to the
+	// producer org and a regulator org, for compliance audits.
+	regulatorCollection = "regulatorCollection"
+)
+
+// collectionMembers mirrors the membership policy declared in
+// collections_config.json, so the chaincode can reject a read before it ever
+// reaches the (peer-enforced) private data access control check. Useful
+// defense in depth, and essential for mock stubs used in tests that don't
+// enforce collection policy at all.
+var collectionMembers = map[string][]string{
+	pricingCollection:   {"Org1MSP", "BuyerMSP"},
+	regulatorCollection: {"Org1MSP", "RegulatorMSP"},
+}
+
+// QRAssetPrivateDetails holds the commercially sensitive fields split out of
+// QRAsset: MRP, net weight, secondary batch, and buyer contract references.
+// It is never written to the public ledger; CreateAsset takes it from the
+// transaction's transient map and ReadAssetPrivate reads it back out of a
+// private data collection.
+type QRAssetPrivateDetails struct {
+	MRP              float64 `json:"mrp"`
+	NetWeight        float64 `json:"net_weight"`
+	SecondaryBatch   string  `json:"secondary_batch"`
+	BuyerContractRef string  `json:"buyer_contract_ref,omitempty"`
+}
+
+func isCollectionMember(mspID, collection string) bool {
+	for _, member := range collectionMembers[collection] {
+		if member == mspID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadAssetPrivate returns the private details of productID from collection.
+// The caller's MSP must be a declared member of that collection.
+func (s *SmartContract) ReadAssetPrivate(ctx contractapi.TransactionContextInterface, productID, collection string) (*QRAssetPrivateDetails, error) {
+	clientMSPID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get client MSP ID: %v", err)
+	}
+	if !isCollectionMember(clientMSPID, collection) {
+		return nil, fmt.Errorf("client MSP %s is not a member of collection %s", clientMSPID, collection)
+	}
+
+	key := fmt.Sprintf("QR:%s", productID)
+	privateJSON, err := ctx.GetStub().GetPrivateData(collection, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private data from %s: %v", collection, err)
+	}
+	if privateJSON == nil {
+		return nil, fmt.Errorf("no private details for asset %s in collection %s", productID, collection)
+	}
+
+	var private QRAssetPrivateDetails
+	if err := json.Unmarshal(privateJSON, &private); err != nil {
+		return nil, err
+	}
+	return &private, nil
+}
@@ -0,0 +1,289 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// CustodyEvent records the transfer of a product from one organization's
+// custody to another at a given stage of the supply chain.
+type CustodyEvent struct {
+	ProductID string  `json:"productId"`
+	FromOrg   string  `json:"fromOrgMSP"`
+	ToOrg     string  `json:"toOrgMSP"`
+	Stage     string  `json:"stage"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timestamp string  `json:"timestamp"`
+	Notes     string  `json:"notes,omitempty"`
+	DocType   string  `json:"docType"`
+}
+
+// QualityEvent records an inspection or quality-control observation made
+// against a product while it is in a given custodian's possession.
+type QualityEvent struct {
+	ProductID    string  `json:"productId"`
+	EventType    string  `json:"eventType"`
+	TemperatureC float64 `json:"temperatureC"`
+	Humidity     float64 `json:"humidity"`
+	InspectorMSP string  `json:"inspectorMSP"`
+	EvidenceHash string  `json:"evidenceHash"`
+	Timestamp    string  `json:"timestamp"`
+	DocType      string  `json:"docType"`
+}
+
+// ProvenanceEntry is one record in a product's timeline, combining ledger
+// history of the asset itself with custody/quality events.
+//
+// There is no EndorsingMSP field: GetHistoryForKey's KeyModification (and
+// GetStateByRange's KV) only carry a tx ID, value, timestamp and delete flag,
+// not the identity of the endorsing organizations, so that information isn't
+// available to reconstruct here.
+type ProvenanceEntry struct {
+	TxID      string             `json:"txId"`
+	Timestamp string             `json:"timestamp"`
+	IsDelete  bool               `json:"isDelete"`
+	Kind      string             `json:"kind"` // "asset", "custody" or "quality"
+	Asset     *QRAsset           `json:"asset,omitempty"`
+	Custody   *CustodyEvent      `json:"custody,omitempty"`
+	Quality   *QualityEvent      `json:"quality,omitempty"`
+	AssetDiff []AssetFieldChange `json:"assetDiff,omitempty"`
+}
+
+// AssetFieldChange is one QRAsset field whose value changed between two
+// consecutive asset-history entries in GetProvenance's timeline.
+type AssetFieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// RecordCustodyTransfer transfers custody of productID from fromOrgMSP to
+// toOrgMSP at the given stage and location. Only the MSP currently holding
+// custody may submit the transfer.
+func (s *SmartContract) RecordCustodyTransfer(ctx contractapi.TransactionContextInterface, productID, fromOrgMSP, toOrgMSP, stage string, latitude, longitude float64, notesJSON string) error {
+	clientMSPID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to get client MSP ID: %v", err)
+	}
+	if clientMSPID != fromOrgMSP {
+		return fmt.Errorf("client MSP %s is not authorized to transfer custody on behalf of %s", clientMSPID, fromOrgMSP)
+	}
+
+	asset, err := s.ReadAsset(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if asset.CustodianMSP != fromOrgMSP {
+		return fmt.Errorf("fromOrgMSP %s does not hold custody of %s (current custodian is %s)", fromOrgMSP, productID, asset.CustodianMSP)
+	}
+
+	txTimestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return fmt.Errorf("failed to get tx timestamp: %v", err)
+	}
+
+	event := CustodyEvent{
+		ProductID: productID,
+		FromOrg:   fromOrgMSP,
+		ToOrg:     toOrgMSP,
+		Stage:     stage,
+		Latitude:  latitude,
+		Longitude: longitude,
+		Timestamp: txTimestamp.AsTime().Format("2006-01-02T15:04:05Z"),
+		Notes:     notesJSON,
+		DocType:   "custodyEvent",
+	}
+
+	key := fmt.Sprintf("EVT:%s:%d", productID, txTimestamp.AsTime().UnixNano())
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(key, eventBytes); err != nil {
+		return fmt.Errorf("failed to put custody event to world state: %v", err)
+	}
+
+	asset.CustodianMSP = toOrgMSP
+	assetKey := fmt.Sprintf("QR:%s", productID)
+	assetBytes, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(assetKey, assetBytes); err != nil {
+		return fmt.Errorf("failed to update custodian on asset %s: %v", productID, err)
+	}
+
+	if err := ctx.GetStub().SetEvent("CustodyTransferred", eventBytes); err != nil {
+		return fmt.Errorf("failed to set event: %v", err)
+	}
+
+	return nil
+}
+
+// RecordQualityEvent attaches an inspection or quality-control observation to
+// productID.
+func (s *SmartContract) RecordQualityEvent(ctx contractapi.TransactionContextInterface, productID, eventType string, temperatureC, humidity float64, inspectorMSP, evidenceHash string) error {
+	exists, err := s.AssetExists(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("the asset %s does not exist", productID)
+	}
+
+	txTimestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return fmt.Errorf("failed to get tx timestamp: %v", err)
+	}
+
+	event := QualityEvent{
+		ProductID:    productID,
+		EventType:    eventType,
+		TemperatureC: temperatureC,
+		Humidity:     humidity,
+		InspectorMSP: inspectorMSP,
+		EvidenceHash: evidenceHash,
+		Timestamp:    txTimestamp.AsTime().Format("2006-01-02T15:04:05Z"),
+		DocType:      "qualityEvent",
+	}
+
+	key := fmt.Sprintf("EVT:%s:%d", productID, txTimestamp.AsTime().UnixNano())
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(key, eventBytes); err != nil {
+		return fmt.Errorf("failed to put quality event to world state: %v", err)
+	}
+
+	if err := ctx.GetStub().SetEvent("QualityEventRecorded", eventBytes); err != nil {
+		return fmt.Errorf("failed to set event: %v", err)
+	}
+
+	return nil
+}
+
+// GetProvenance returns the full timeline for productID: every historical
+// version of the asset (via GetHistoryForKey) interleaved with its custody
+// and quality events, ordered oldest first.
+func (s *SmartContract) GetProvenance(ctx contractapi.TransactionContextInterface, productID string) ([]*ProvenanceEntry, error) {
+	var timeline []*ProvenanceEntry
+
+	assetKey := fmt.Sprintf("QR:%s", productID)
+	historyIterator, err := ctx.GetStub().GetHistoryForKey(assetKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history for %s: %v", productID, err)
+	}
+	defer historyIterator.Close()
+
+	for historyIterator.HasNext() {
+		mod, err := historyIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		entry := &ProvenanceEntry{
+			TxID:      mod.TxId,
+			Timestamp: mod.Timestamp.AsTime().Format("2006-01-02T15:04:05Z"),
+			IsDelete:  mod.IsDelete,
+			Kind:      "asset",
+		}
+		if !mod.IsDelete {
+			var asset QRAsset
+			if err := json.Unmarshal(mod.Value, &asset); err != nil {
+				return nil, err
+			}
+			entry.Asset = &asset
+		}
+		timeline = append(timeline, entry)
+	}
+	// GetHistoryForKey yields newest-first; reverse so the asset history is
+	// oldest-first and same-second versions keep their write order below.
+	for i, j := 0, len(timeline)-1; i < j; i, j = i+1, j-1 {
+		timeline[i], timeline[j] = timeline[j], timeline[i]
+	}
+
+	eventIterator, err := ctx.GetStub().GetStateByRange(fmt.Sprintf("EVT:%s:", productID), fmt.Sprintf("EVT:%s:~", productID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to range over events for %s: %v", productID, err)
+	}
+	defer eventIterator.Close()
+
+	for eventIterator.HasNext() {
+		kv, err := eventIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var probe struct {
+			DocType string `json:"docType"`
+		}
+		if err := json.Unmarshal(kv.Value, &probe); err != nil {
+			return nil, err
+		}
+
+		switch probe.DocType {
+		case "custodyEvent":
+			var custody CustodyEvent
+			if err := json.Unmarshal(kv.Value, &custody); err != nil {
+				return nil, err
+			}
+			timeline = append(timeline, &ProvenanceEntry{Kind: "custody", Timestamp: custody.Timestamp, Custody: &custody})
+		case "qualityEvent":
+			var quality QualityEvent
+			if err := json.Unmarshal(kv.Value, &quality); err != nil {
+				return nil, err
+			}
+			timeline = append(timeline, &ProvenanceEntry{Kind: "quality", Timestamp: quality.Timestamp, Quality: &quality})
+		}
+	}
+
+	// The asset history above and the custody/quality events (per
+	// GetStateByRange) are each oldest-first; merge them into a single
+	// oldest-first timeline.
+	sort.SliceStable(timeline, func(i, j int) bool {
+		return timeline[i].Timestamp < timeline[j].Timestamp
+	})
+
+	var prevAsset *QRAsset
+	for _, entry := range timeline {
+		if entry.Kind != "asset" || entry.IsDelete {
+			continue
+		}
+		if prevAsset != nil {
+			entry.AssetDiff = diffAssetFields(prevAsset, entry.Asset)
+		}
+		prevAsset = entry.Asset
+	}
+
+	return timeline, nil
+}
+
+// diffAssetFields reports every QRAsset field whose value differs between
+// prev and curr, so GetProvenance's timeline shows what changed at each
+// asset-history entry instead of just the full before/after snapshots.
+func diffAssetFields(prev, curr *QRAsset) []AssetFieldChange {
+	prevVal := reflect.ValueOf(*prev)
+	currVal := reflect.ValueOf(*curr)
+	t := prevVal.Type()
+
+	var changes []AssetFieldChange
+	for i := 0; i < t.NumField(); i++ {
+		pf := prevVal.Field(i).Interface()
+		cf := currVal.Field(i).Interface()
+		if reflect.DeepEqual(pf, cf) {
+			continue
+		}
+		changes = append(changes, AssetFieldChange{
+			Field: t.Field(i).Name,
+			From:  fmt.Sprintf("%v", pf),
+			To:    fmt.Sprintf("%v", cf),
+		})
+	}
+	return changes
+}
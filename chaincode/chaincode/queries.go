@@ -0,0 +1,127 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+)
+
+// PaginatedQueryResult wraps a page of assets along with the bookmark needed
+// to fetch the next page, so a UI can paginate through large result sets
+// instead of pulling the whole world state at once.
+type PaginatedQueryResult struct {
+	Results      []*QRAsset `json:"results"`
+	Bookmark     string     `json:"bookmark"`
+	FetchedCount int32      `json:"fetchedCount"`
+}
+
+// QueryAssetsBySpecies returns assets matching the given English species name.
+func (s *SmartContract) QueryAssetsBySpecies(ctx contractapi.TransactionContextInterface, species string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	selector := map[string]interface{}{
+		"docType":    "asset",
+		"species_en": species,
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+// QueryAssetsByProducer returns assets produced by the given organization.
+func (s *SmartContract) QueryAssetsByProducer(ctx contractapi.TransactionContextInterface, org string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	selector := map[string]interface{}{
+		"docType":                  "asset",
+		"producer_organization_en": org,
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+// QueryAssetsByBatch returns assets matching the given batch and lot
+// numbers. Empty arguments are treated as "don't filter on this field".
+// SecondaryBatch lives in pricingCollection (see ReadAssetPrivate) and isn't
+// queryable here since private data collections aren't covered by the
+// public state database's rich-query index.
+func (s *SmartContract) QueryAssetsByBatch(ctx contractapi.TransactionContextInterface, batch, lot string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	selector := map[string]interface{}{
+		"docType": "asset",
+	}
+	if batch != "" {
+		selector["batch_number"] = batch
+	}
+	if lot != "" {
+		selector["lot_number"] = lot
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+// QueryAssetsByHarvestDateRange returns assets harvested between from and to
+// (both inclusive, formatted as "YYYY-MM-DD" to match DateOfHarvesting).
+func (s *SmartContract) QueryAssetsByHarvestDateRange(ctx contractapi.TransactionContextInterface, from, to string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	selector := map[string]interface{}{
+		"docType": "asset",
+		"date_of_harvesting": map[string]interface{}{
+			"$gte": from,
+			"$lte": to,
+		},
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+// QueryAssetsByGeoBox returns assets whose production coordinates fall inside
+// the given bounding box.
+func (s *SmartContract) QueryAssetsByGeoBox(ctx contractapi.TransactionContextInterface, minLat, minLng, maxLat, maxLng float64, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	selector := map[string]interface{}{
+		"docType": "asset",
+		"production_latitude": map[string]interface{}{
+			"$gte": minLat,
+			"$lte": maxLat,
+		},
+		"production_longitude": map[string]interface{}{
+			"$gte": minLng,
+			"$lte": maxLng,
+		},
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+// QueryAssets is a generic escape hatch for ad-hoc Mango selectors that don't
+// warrant a dedicated transaction. selectorJSON must be a valid CouchDB Mango
+// selector, e.g. `{"docType":"asset","species_en":"Hilsa"}`.
+func (s *SmartContract) QueryAssets(ctx contractapi.TransactionContextInterface, selectorJSON string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	var selector map[string]interface{}
+	if err := json.Unmarshal([]byte(selectorJSON), &selector); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal selector: %v", err)
+	}
+	return s.queryAssetsWithPagination(ctx, selector, pageSize, bookmark)
+}
+
+func (s *SmartContract) queryAssetsWithPagination(ctx contractapi.TransactionContextInterface, selector map[string]interface{}, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	queryString, err := json.Marshal(map[string]interface{}{"selector": selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query selector: %v", err)
+	}
+
+	resultsIterator, responseMetadata, err := ctx.GetStub().GetQueryResultWithPagination(string(queryString), pageSize, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute rich query: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	var assets []*QRAsset
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var asset QRAsset
+		if err := json.Unmarshal(queryResponse.Value, &asset); err != nil {
+			return nil, err
+		}
+		assets = append(assets, &asset)
+	}
+
+	return &PaginatedQueryResult{
+		Results:      assets,
+		Bookmark:     responseMetadata.Bookmark,
+		FetchedCount: responseMetadata.FetchedRecordsCount,
+	}, nil
+}
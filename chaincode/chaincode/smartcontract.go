@@ -11,6 +11,9 @@ type SmartContract struct {
 	contractapi.Contract
 }
 
+// QRAsset is the public record for a product, replicated to every peer on
+// the channel. Commercially sensitive fields (price, weight, buyer identity)
+// live in private data collections instead — see QRAssetPrivateDetails.
 type QRAsset struct {
 	ProductID                          string   `json:"productId"`
 	ProductNameEn                      string   `json:"product_name_en"`
@@ -20,7 +23,6 @@ type QRAsset struct {
 	DateOfHarvesting                   string   `json:"date_of_harvesting"`
 	DateOfPackaging                    string   `json:"date_of_packaging"`
 	ExpiredDate                        string   `json:"expired_date"`
-	MRP                                float64  `json:"mrp"`
 	HasBlastFreezer                    bool     `json:"has_blast_freezer"`
 	HasIQF                             bool     `json:"has_iqf"`
 	HasVacuumPackage                   bool     `json:"has_vacuum_package"`
@@ -31,9 +33,7 @@ type QRAsset struct {
 	WaterSourceBn                      []string `json:"water_source_bn"`
 	HasFreezerVanTransportation        bool     `json:"has_freezer_van_transportation"`
 	BatchNumber                        string   `json:"batch_number"`
-	SecondaryBatch                     string   `json:"secondary_batch"`
 	LotNumber                          string   `json:"lot_number"`
-	NetWeight                          float64  `json:"net_weight"`
 	CertificationEn                    []string `json:"certification_en"`
 	CertificationBn                    []string `json:"certification_bn"`
 	CertificationLink                  []string `json:"certification_link"`
@@ -49,9 +49,28 @@ type QRAsset struct {
 	LivestockProcessingUnitLongitude   float64  `json:"livestock_processing_unit_longitude"`
 	ProcessorOrganizationEn            string   `json:"processor_organization_en"`
 	ProcessorOrganizationBn            string   `json:"processor_organization_bn"`
+	Attachments                        []Attachment `json:"attachments,omitempty"`
+	// CustodianMSP is the MSP currently holding custody of the product, set to
+	// the creating org on CreateAsset and advanced by RecordCustodyTransfer;
+	// see provenance.go.
+	CustodianMSP                       string   `json:"custodian_msp"`
 	DocType                            string   `json:"docType"`
 }
 
+// Attachment points at an off-chain, content-addressed document (e.g. a
+// certification PDF or lab report) without storing the payload itself on the
+// ledger. CID and SHA256 make the reference tamper-evident: VerifyAttachment
+// reports whether a CID/SHA-256 pair, computed by the client from the bytes
+// it fetched, matches one recorded here.
+type Attachment struct {
+	Kind      string `json:"kind"`
+	CID       string `json:"cid"`
+	SHA256    string `json:"sha256"`
+	MimeType  string `json:"mimeType"`
+	SizeBytes int64  `json:"sizeBytes"`
+	URI       string `json:"uri"`
+}
+
 func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
 	assets := []QRAsset{
 		{
@@ -63,7 +82,6 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 			DateOfHarvesting: "2025-09-01",
 			DateOfPackaging: "2025-09-03",
 			ExpiredDate: "2026-03-01",
-			MRP: 1200.5,
 			HasBlastFreezer: true,
 			HasIQF: false,
 			HasVacuumPackage: true,
@@ -74,9 +92,7 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 			WaterSourceBn: []string{"Filtered water", "Arsenic"},
 			HasFreezerVanTransportation: true,
 			BatchNumber: "BATCH-001",
-			SecondaryBatch: "SBATCH-001",
 			LotNumber: "LOT-001",
-			NetWeight: 2.5,
 			CertificationEn: []string{"ISO22000", "HACCP"},
 			CertificationBn: []string{"ISO22000", "HACCP"},
 			CertificationLink: []string{"https://iso.org/22000", "https://haccp.org"},
@@ -92,10 +108,19 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 			LivestockProcessingUnitLongitude: 90.39,
 			ProcessorOrganizationEn: "Bangladesh Fish Processing Ltd",
 			ProcessorOrganizationBn: "Bangladesh Fish Processing Ltd",
+			CustodianMSP: "Org1MSP",
 			DocType: "asset",
 		},
 	}
 
+	privateDetails := map[string]QRAssetPrivateDetails{
+		"0": {
+			MRP:            1200.5,
+			NetWeight:      2.5,
+			SecondaryBatch: "SBATCH-001",
+		},
+	}
+
 	for _, asset := range assets {
 		key := fmt.Sprintf("QR:%s", asset.ProductID)
 		assetJSON, err := json.Marshal(asset)
@@ -105,6 +130,19 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 		if err := ctx.GetStub().PutState(key, assetJSON); err != nil {
 			return fmt.Errorf("failed to put to world state: %v", err)
 		}
+
+		if private, ok := privateDetails[asset.ProductID]; ok {
+			privateJSON, err := json.Marshal(private)
+			if err != nil {
+				return err
+			}
+			if err := ctx.GetStub().PutPrivateData(pricingCollection, key, privateJSON); err != nil {
+				return fmt.Errorf("failed to put private data to %s: %v", pricingCollection, err)
+			}
+			if err := ctx.GetStub().PutPrivateData(regulatorCollection, key, privateJSON); err != nil {
+				return fmt.Errorf("failed to put private data to %s: %v", regulatorCollection, err)
+			}
+		}
 	}
 	return nil
 }
@@ -118,6 +156,13 @@ func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface,
 	return assetJSON != nil, nil
 }
 
+// CreateAsset stores the public fields of a new asset from assetJSON. Any
+// pricing-sensitive fields (mrp, net_weight, secondary_batch) must instead be
+// submitted via the transient map under the "asset_private" key, so they
+// never appear in the (channel-wide, ordered) transaction proposal; see
+// QRAssetPrivateDetails. The same private details are written to both
+// pricingCollection (producer/buyer) and regulatorCollection (producer/
+// regulator) so ReadAssetPrivate works against either for its intended org.
 func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, assetJSON string) error {
 	var asset QRAsset
 	if err := json.Unmarshal([]byte(assetJSON), &asset); err != nil {
@@ -132,7 +177,30 @@ func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface,
 		return fmt.Errorf("the asset %s already exists", asset.ProductID)
 	}
 
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf("failed to get transient data: %v", err)
+	}
+	privateJSON, ok := transientMap["asset_private"]
+	if !ok {
+		return fmt.Errorf("asset_private key not found in the transient map")
+	}
+	var private QRAssetPrivateDetails
+	if err := json.Unmarshal(privateJSON, &private); err != nil {
+		return fmt.Errorf("failed to unmarshal private asset details: %v", err)
+	}
+	privateBytes, err := json.Marshal(private)
+	if err != nil {
+		return err
+	}
+
+	clientMSPID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to get client MSP ID: %v", err)
+	}
+
 	asset.DocType = "asset"
+	asset.CustodianMSP = clientMSPID
 	key := fmt.Sprintf("QR:%s", asset.ProductID)
 	assetBytes, err := json.Marshal(asset)
 	if err != nil {
@@ -143,6 +211,13 @@ func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface,
 		return err
 	}
 
+	if err := ctx.GetStub().PutPrivateData(pricingCollection, key, privateBytes); err != nil {
+		return fmt.Errorf("failed to put private data to %s: %v", pricingCollection, err)
+	}
+	if err := ctx.GetStub().PutPrivateData(regulatorCollection, key, privateBytes); err != nil {
+		return fmt.Errorf("failed to put private data to %s: %v", regulatorCollection, err)
+	}
+
 	event := map[string]string{"productId": asset.ProductID}
 	eventBytes, _ := json.Marshal(event)
 	if err := ctx.GetStub().SetEvent("QRCreated", eventBytes); err != nil {
@@ -0,0 +1,45 @@
+package conformance
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "regenerate testdata/vectors/*.json from the current chaincode implementation")
+
+const vectorsDir = "testdata/vectors"
+
+func TestConformance(t *testing.T) {
+	vectors, err := LoadVectors(vectorsDir)
+	if err != nil {
+		t.Fatalf("failed to load vectors: %v", err)
+	}
+	if len(vectors) == 0 {
+		t.Fatalf("no vectors found in %s", vectorsDir)
+	}
+
+	for _, v := range vectors {
+		v := v
+		t.Run(v.Name, func(t *testing.T) {
+			if *update {
+				updated, err := updateVector(v)
+				if err != nil {
+					t.Fatalf("failed to regenerate vector: %v", err)
+				}
+				if err := SaveVector(filepath.Join(vectorsDir, v.Name+".json"), updated); err != nil {
+					t.Fatalf("failed to save vector: %v", err)
+				}
+				return
+			}
+
+			report, err := Run(v)
+			if err != nil {
+				t.Fatalf("failed to run vector: %v", err)
+			}
+			if !report.Passed() {
+				t.Error(report.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package conformance
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-chaincode-go/v2/shim"
+	"github.com/hyperledger/fabric-contract-api-go/v2/contractapi"
+
+	"github.com/Meharab/meat/chaincode/chaincode"
+)
+
+// StepResult is what actually happened when a Step ran, for diffing against
+// its expectations and for `-update` to write back as the new expected side.
+type StepResult struct {
+	Err          error
+	ReturnValue  string
+	EventName    string
+	EventPayload string
+}
+
+// Report is the outcome of running a Vector: one StepResult per step, plus
+// any world-state mismatches found afterward.
+type Report struct {
+	VectorName    string
+	StepFailures  []string
+	StateFailures []string
+	Results       []StepResult
+}
+
+// Passed reports whether every assertion in the vector held.
+func (r Report) Passed() bool {
+	return len(r.StepFailures) == 0 && len(r.StateFailures) == 0
+}
+
+// String renders a compact human-readable report, one line per failure.
+func (r Report) String() string {
+	if r.Passed() {
+		return fmt.Sprintf("%s: PASS (%d steps)", r.VectorName, len(r.Results))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: FAIL\n", r.VectorName)
+	for _, f := range r.StepFailures {
+		fmt.Fprintf(&b, "  step: %s\n", f)
+	}
+	for _, f := range r.StateFailures {
+		fmt.Fprintf(&b, "  state: %s\n", f)
+	}
+	return b.String()
+}
+
+// Run executes v against a freshly constructed mock chaincode instance and
+// diffs the actual outcome of each step, and the final world state, against
+// what v expects.
+func Run(v Vector) (Report, error) {
+	report := Report{VectorName: v.Name}
+
+	cc, err := contractapi.NewChaincode(&chaincode.SmartContract{})
+	if err != nil {
+		return report, fmt.Errorf("failed to build chaincode: %w", err)
+	}
+	stub := NewMockStub(v.Name, cc)
+
+	for i, step := range v.Steps {
+		result := invokeStep(stub, step)
+		report.Results = append(report.Results, result)
+
+		if msg := diffStep(i, step, result); msg != "" {
+			report.StepFailures = append(report.StepFailures, msg)
+		}
+	}
+
+	for _, expected := range v.ExpectedState {
+		actual := string(stub.State[expected.Key])
+		if !strings.Contains(actual, expected.ValueContains) {
+			report.StateFailures = append(report.StateFailures, fmt.Sprintf(
+				"key %q: expected value to contain %q, got %q", expected.Key, expected.ValueContains, actual))
+		}
+	}
+
+	return report, nil
+}
+
+func invokeStep(stub *MockStub, step Step) StepResult {
+	args := make([][]byte, 0, len(step.Args)+1)
+	args = append(args, []byte(step.Function))
+	for _, a := range step.Args {
+		args = append(args, []byte(a))
+	}
+
+	transient := make(map[string][]byte, len(step.Transient))
+	for k, v := range step.Transient {
+		transient[k] = []byte(v)
+	}
+	stub.TransientMap = transient
+
+	resp := stub.MockInvoke(step.Function, args)
+
+	result := StepResult{ReturnValue: string(resp.Payload)}
+	if resp.Status != shim.OK {
+		result.Err = fmt.Errorf("%s", resp.Message)
+		return result
+	}
+
+	for _, evt := range stub.ChaincodeEventsPublished {
+		result.EventName = evt.EventName
+		result.EventPayload = string(evt.Payload)
+	}
+	return result
+}
+
+func diffStep(index int, step Step, result StepResult) string {
+	if step.ExpectError {
+		if result.Err == nil {
+			return fmt.Sprintf("step %d (%s): expected an error, got none", index, step.Function)
+		}
+		if step.ExpectedErrorContains != "" && !strings.Contains(result.Err.Error(), step.ExpectedErrorContains) {
+			return fmt.Sprintf("step %d (%s): expected error to contain %q, got %q", index, step.Function, step.ExpectedErrorContains, result.Err.Error())
+		}
+		return ""
+	}
+	if result.Err != nil {
+		return fmt.Sprintf("step %d (%s): unexpected error: %v", index, step.Function, result.Err)
+	}
+	if step.ExpectedReturnContains != "" && !strings.Contains(result.ReturnValue, step.ExpectedReturnContains) {
+		return fmt.Sprintf("step %d (%s): expected return value to contain %q, got %q", index, step.Function, step.ExpectedReturnContains, result.ReturnValue)
+	}
+	if step.ExpectedEvent != nil {
+		if result.EventName != step.ExpectedEvent.Name {
+			return fmt.Sprintf("step %d (%s): expected event %q, got %q", index, step.Function, step.ExpectedEvent.Name, result.EventName)
+		}
+		if step.ExpectedEvent.PayloadContains != "" && !strings.Contains(result.EventPayload, step.ExpectedEvent.PayloadContains) {
+			return fmt.Sprintf("step %d (%s): expected event payload to contain %q, got %q", index, step.Function, step.ExpectedEvent.PayloadContains, result.EventPayload)
+		}
+	}
+	return ""
+}
+
+// updateVector re-runs v and rewrites the expected side of every step (and
+// the final world-state snapshot) to match what actually happened, for
+// `go test ./chaincode/conformance -update`.
+func updateVector(v Vector) (Vector, error) {
+	cc, err := contractapi.NewChaincode(&chaincode.SmartContract{})
+	if err != nil {
+		return v, fmt.Errorf("failed to build chaincode: %w", err)
+	}
+	stub := NewMockStub(v.Name, cc)
+
+	updated := v
+	for i, step := range v.Steps {
+		result := invokeStep(stub, step)
+
+		if result.Err != nil {
+			updated.Steps[i].ExpectError = true
+			updated.Steps[i].ExpectedErrorContains = result.Err.Error()
+			updated.Steps[i].ExpectedReturnContains = ""
+			updated.Steps[i].ExpectedEvent = nil
+			continue
+		}
+		updated.Steps[i].ExpectError = false
+		updated.Steps[i].ExpectedErrorContains = ""
+		updated.Steps[i].ExpectedReturnContains = result.ReturnValue
+		if result.EventName != "" {
+			updated.Steps[i].ExpectedEvent = &EventExpectation{Name: result.EventName, PayloadContains: result.EventPayload}
+		} else {
+			updated.Steps[i].ExpectedEvent = nil
+		}
+	}
+
+	keys := make([]string, 0, len(stub.State))
+	for key := range stub.State {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	state := make([]KeyValueExpectation, 0, len(keys))
+	for _, key := range keys {
+		state = append(state, KeyValueExpectation{Key: key, ValueContains: string(stub.State[key])})
+	}
+	updated.ExpectedState = state
+
+	return updated, nil
+}
@@ -0,0 +1,44 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LoadVectors reads and parses every *.json file in dir, sorted by filename
+// so a run's ordering is deterministic.
+func LoadVectors(dir string) ([]Vector, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to glob %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	vectors := make([]Vector, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var v Vector
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", path, err)
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+// SaveVector writes v back to path as indented JSON, for `-update` mode.
+func SaveVector(path string, v Vector) error {
+	data, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to marshal vector %s: %w", v.Name, err)
+	}
+	data = append(data, '\n')
+	return os.WriteFile(path, data, 0o644)
+}
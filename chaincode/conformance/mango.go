@@ -0,0 +1,93 @@
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/queryresult"
+)
+
+// newMangoIterator evaluates queryJSON (a `{"selector": {...}}` CouchDB Mango
+// query) against state and returns the matching documents in key order. It
+// only understands the shapes queryAssetsWithPagination ever builds: a flat
+// map of field-to-value equality checks, plus an optional single-level
+// {"$gte": x, "$lte": y} range per field. That's enough to run this
+// chaincode's rich queries against an in-memory mock without vendoring a
+// real Mango evaluator.
+func newMangoIterator(state map[string][]byte, queryJSON string) (*staticKVIterator, error) {
+	var parsed struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(queryJSON), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal Mango query: %w", err)
+	}
+
+	base := newKVIterator(state, "", "")
+	var matched []*queryresult.KV
+	for base.HasNext() {
+		kv, err := base.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var doc map[string]interface{}
+		if err := json.Unmarshal(kv.Value, &doc); err != nil {
+			continue // not a JSON object; never matches.
+		}
+		if matchesSelector(doc, parsed.Selector) {
+			matched = append(matched, kv)
+		}
+	}
+	return &staticKVIterator{kvs: matched}, nil
+}
+
+func matchesSelector(doc, selector map[string]interface{}) bool {
+	for field, want := range selector {
+		got, ok := doc[field]
+		if rng, isRange := want.(map[string]interface{}); isRange {
+			if !ok || !inRange(got, rng) {
+				return false
+			}
+			continue
+		}
+		if !ok || !equalJSON(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+func inRange(got interface{}, rng map[string]interface{}) bool {
+	gotF, ok := toFloat(got)
+	if !ok {
+		gotS, isStr := got.(string)
+		if !isStr {
+			return false
+		}
+		if gte, ok := rng["$gte"].(string); ok && gotS < gte {
+			return false
+		}
+		if lte, ok := rng["$lte"].(string); ok && gotS > lte {
+			return false
+		}
+		return true
+	}
+	if gte, ok := toFloat(rng["$gte"]); ok && gotF < gte {
+		return false
+	}
+	if lte, ok := toFloat(rng["$lte"]); ok && gotF > lte {
+		return false
+	}
+	return true
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func equalJSON(a, b interface{}) bool {
+	aj, _ := json.Marshal(a)
+	bj, _ := json.Marshal(b)
+	return string(aj) == string(bj)
+}
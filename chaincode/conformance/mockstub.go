@@ -0,0 +1,515 @@
+package conformance
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/v2/shim"
+	"github.com/hyperledger/fabric-protos-go-apiv2/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go-apiv2/msp"
+	"github.com/hyperledger/fabric-protos-go-apiv2/peer"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ChaincodeEvent is the subset of a chaincode event the harness needs to
+// assert on. It mirrors peer.ChaincodeEvent without depending on it, since
+// SetEvent on the real stub only ever needs to round-trip name and payload
+// here.
+type ChaincodeEvent struct {
+	EventName string
+	Payload   []byte
+}
+
+// historyRecord is one version of a key, in write order, for GetHistoryForKey.
+type historyRecord struct {
+	txID      string
+	timestamp *timestamppb.Timestamp
+	value     []byte
+	isDelete  bool
+}
+
+// MockStub is an in-memory implementation of shim.ChaincodeStubInterface,
+// enough of one to drive a contractapi.ContractChaincode end to end for the
+// conformance vectors: world state, private data collections, transient
+// maps, events and a deterministic tx clock. fabric-chaincode-go/v2 doesn't
+// ship a shimtest package (that only exists for the pre-v2, non-contractapi
+// shim.Chaincode interface), so this is the vendored replacement.
+type MockStub struct {
+	name  string
+	cc    shim.Chaincode
+	mspID string
+
+	State        map[string][]byte
+	PrivateState map[string]map[string][]byte
+	TransientMap map[string][]byte
+
+	ChaincodeEventsPublished []ChaincodeEvent
+
+	history map[string][]historyRecord
+
+	creator   []byte
+	txCounter int
+	args      [][]byte
+	txID      string
+	timestamp *timestamppb.Timestamp
+}
+
+// NewMockStub builds a MockStub named name, identified to the chaincode as
+// MSP "Org1MSP" (the org every collection and authorization check in this
+// chaincode treats as the default caller).
+func NewMockStub(name string, cc shim.Chaincode) *MockStub {
+	creator, err := newMockCreator("Org1MSP")
+	if err != nil {
+		panic(fmt.Sprintf("conformance: failed to build mock creator identity: %v", err))
+	}
+	return &MockStub{
+		name:         name,
+		cc:           cc,
+		mspID:        "Org1MSP",
+		State:        map[string][]byte{},
+		PrivateState: map[string]map[string][]byte{},
+		history:      map[string][]historyRecord{},
+		creator:      creator,
+	}
+}
+
+// MockInvoke runs function(args...) against the wrapped chaincode, assigning
+// a fresh deterministic TxID and timestamp, and resetting the events
+// collected from the previous invocation so each step sees only its own.
+func (m *MockStub) MockInvoke(function string, args [][]byte) *peer.Response {
+	m.txCounter++
+	m.txID = fmt.Sprintf("tx%d", m.txCounter)
+	m.timestamp = timestamppb.New(time.Unix(1700000000+int64(m.txCounter), 0).UTC())
+	m.args = args
+	m.ChaincodeEventsPublished = nil
+
+	resp := m.cc.Invoke(m)
+	return resp
+}
+
+func newMockCreator(mspID string) ([]byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate mock identity key: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "conformance-mock"},
+		NotBefore:    time.Unix(1700000000, 0).UTC(),
+		NotAfter:     time.Unix(1700000000, 0).UTC().AddDate(10, 0, 0),
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to self-sign mock identity cert: %w", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	identity := &msp.SerializedIdentity{Mspid: mspID, IdBytes: certPEM}
+	creator, err := proto.Marshal(identity)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mock identity: %w", err)
+	}
+	return creator, nil
+}
+
+// --- transaction/proposal metadata ---
+
+func (m *MockStub) GetArgs() [][]byte { return m.args }
+
+func (m *MockStub) GetStringArgs() []string {
+	args := make([]string, len(m.args))
+	for i, a := range m.args {
+		args[i] = string(a)
+	}
+	return args
+}
+
+func (m *MockStub) GetFunctionAndParameters() (string, []string) {
+	args := m.GetStringArgs()
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
+
+func (m *MockStub) GetArgsSlice() ([]byte, error) {
+	return bytesJoin(m.args), nil
+}
+
+func (m *MockStub) GetTxID() string      { return m.txID }
+func (m *MockStub) GetChannelID() string { return "mockchannel" }
+
+func (m *MockStub) GetTxTimestamp() (*timestamppb.Timestamp, error) {
+	return m.timestamp, nil
+}
+
+func (m *MockStub) GetCreator() ([]byte, error) { return m.creator, nil }
+
+func (m *MockStub) GetTransient() (map[string][]byte, error) {
+	return m.TransientMap, nil
+}
+
+func (m *MockStub) GetBinding() ([]byte, error) { return nil, nil }
+
+func (m *MockStub) GetDecorations() map[string][]byte { return nil }
+
+func (m *MockStub) GetSignedProposal() (*peer.SignedProposal, error) {
+	return nil, fmt.Errorf("GetSignedProposal is not supported by the conformance mock")
+}
+
+func (m *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, channel string) *peer.Response {
+	return &peer.Response{Status: shim.ERROR, Message: "cross-chaincode invocation is not supported by the conformance mock"}
+}
+
+func (m *MockStub) SetEvent(name string, payload []byte) error {
+	if name == "" {
+		return fmt.Errorf("event name must not be empty")
+	}
+	m.ChaincodeEventsPublished = append(m.ChaincodeEventsPublished, ChaincodeEvent{EventName: name, Payload: payload})
+	return nil
+}
+
+// --- world state ---
+
+func (m *MockStub) GetState(key string) ([]byte, error) {
+	return m.State[key], nil
+}
+
+// GetMultipleStates returns the values for keys in the order given, with a
+// nil entry for any key missing from State.
+func (m *MockStub) GetMultipleStates(keys ...string) ([][]byte, error) {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = m.State[key]
+	}
+	return values, nil
+}
+
+func (m *MockStub) PutState(key string, value []byte) error {
+	if key == "" {
+		return fmt.Errorf("key must not be empty")
+	}
+	m.State[key] = value
+	m.history[key] = append(m.history[key], historyRecord{txID: m.txID, timestamp: m.timestamp, value: value})
+	return nil
+}
+
+func (m *MockStub) DelState(key string) error {
+	delete(m.State, key)
+	m.history[key] = append(m.history[key], historyRecord{txID: m.txID, timestamp: m.timestamp, isDelete: true})
+	return nil
+}
+
+// StartWriteBatch and FinishWriteBatch are no-ops here: the mock applies
+// PutState/DelState to State immediately rather than buffering them into a
+// batch to flush.
+func (m *MockStub) StartWriteBatch() {}
+
+func (m *MockStub) FinishWriteBatch() error { return nil }
+
+func (m *MockStub) SetStateValidationParameter(key string, ep []byte) error { return nil }
+
+func (m *MockStub) GetStateValidationParameter(key string) ([]byte, error) { return nil, nil }
+
+func (m *MockStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	return newKVIterator(m.State, startKey, endKey), nil
+}
+
+func (m *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	base := newKVIterator(m.State, startKey, endKey)
+	it, meta := paginate(base, pageSize, bookmark)
+	return it, meta, nil
+}
+
+func (m *MockStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
+	return shim.CreateCompositeKey(objectType, attributes)
+}
+
+// SplitCompositeKey reverses CreateCompositeKey. shim only exports the
+// composite-key format through the CreateCompositeKey free function, not a
+// matching split, so this replays the same \x00-delimited layout locally.
+func (m *MockStub) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	return splitCompositeKey(compositeKey)
+}
+
+const minUnicodeRuneValue = 0
+
+func splitCompositeKey(compositeKey string) (string, []string, error) {
+	componentIndex := 1
+	var components []string
+	for i := 1; i < len(compositeKey); i++ {
+		if compositeKey[i] == minUnicodeRuneValue {
+			components = append(components, compositeKey[componentIndex:i])
+			componentIndex = i + 1
+		}
+	}
+	if len(components) < 1 {
+		return "", nil, fmt.Errorf("invalid composite key %q: no components found", compositeKey)
+	}
+	return components[0], components[1:], nil
+}
+
+func (m *MockStub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := shim.CreateCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, err
+	}
+	return newKVIterator(m.State, prefix, prefix+string(rune(0x10FFFF))), nil
+}
+
+func (m *MockStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	it, err := m.GetStateByPartialCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	it, meta := paginate(it, pageSize, bookmark)
+	return it, meta, nil
+}
+
+// GetAllStatesCompositeKeyWithPagination ranges over every composite key in
+// State, i.e. every key carrying the \x00 composite-key namespace prefix
+// CreateCompositeKey produces.
+func (m *MockStub) GetAllStatesCompositeKeyWithPagination(pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	compositeOnly := map[string][]byte{}
+	for k, v := range m.State {
+		if len(k) > 0 && k[0] == minUnicodeRuneValue {
+			compositeOnly[k] = v
+		}
+	}
+	base := newKVIterator(compositeOnly, "", "")
+	it, meta := paginate(base, pageSize, bookmark)
+	return it, meta, nil
+}
+
+// GetQueryResult evaluates a (small subset of) CouchDB Mango selector against
+// the public world state: this chaincode's selectors are either flat
+// field-equality or a single-level $gte/$lte range, which is all
+// queryAssetsWithPagination ever builds.
+func (m *MockStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	return newMangoIterator(m.State, query)
+}
+
+func (m *MockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	it, err := newMangoIterator(m.State, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	resultIt, meta := paginate(it, pageSize, bookmark)
+	return resultIt, meta, nil
+}
+
+func (m *MockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
+	return &historyIterator{records: m.history[key]}, nil
+}
+
+// --- private data ---
+
+func (m *MockStub) collection(collection string) map[string][]byte {
+	c, ok := m.PrivateState[collection]
+	if !ok {
+		c = map[string][]byte{}
+		m.PrivateState[collection] = c
+	}
+	return c
+}
+
+func (m *MockStub) GetPrivateData(collection, key string) ([]byte, error) {
+	return m.collection(collection)[key], nil
+}
+
+// GetMultiplePrivateData returns the values for keys in collection in the
+// order given, with a nil entry for any key missing from the collection.
+func (m *MockStub) GetMultiplePrivateData(collection string, keys ...string) ([][]byte, error) {
+	c := m.collection(collection)
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = c[key]
+	}
+	return values, nil
+}
+
+func (m *MockStub) GetPrivateDataHash(collection, key string) ([]byte, error) {
+	return nil, fmt.Errorf("GetPrivateDataHash is not supported by the conformance mock")
+}
+
+func (m *MockStub) PutPrivateData(collection string, key string, value []byte) error {
+	m.collection(collection)[key] = value
+	return nil
+}
+
+func (m *MockStub) DelPrivateData(collection, key string) error {
+	delete(m.collection(collection), key)
+	return nil
+}
+
+func (m *MockStub) PurgePrivateData(collection, key string) error {
+	delete(m.collection(collection), key)
+	return nil
+}
+
+func (m *MockStub) SetPrivateDataValidationParameter(collection, key string, ep []byte) error {
+	return nil
+}
+
+func (m *MockStub) GetPrivateDataValidationParameter(collection, key string) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
+	return newKVIterator(m.collection(collection), startKey, endKey), nil
+}
+
+func (m *MockStub) GetPrivateDataByPartialCompositeKey(collection, objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := shim.CreateCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, err
+	}
+	return newKVIterator(m.collection(collection), prefix, prefix+string(rune(0x10FFFF))), nil
+}
+
+func (m *MockStub) GetPrivateDataQueryResult(collection, query string) (shim.StateQueryIteratorInterface, error) {
+	return newMangoIterator(m.collection(collection), query)
+}
+
+func bytesJoin(parts [][]byte) []byte {
+	var out []byte
+	for i, p := range parts {
+		if i > 0 {
+			out = append(out, 0)
+		}
+		out = append(out, p...)
+	}
+	return out
+}
+
+// --- iterators ---
+
+// kvIterator walks a snapshot of a map in lexicographic key order between
+// [startKey, endKey), mirroring GetStateByRange's CouchDB/LevelDB semantics.
+// "" for both bounds means "everything".
+type kvIterator struct {
+	keys []string
+	vals map[string][]byte
+	pos  int
+}
+
+func newKVIterator(state map[string][]byte, startKey, endKey string) *kvIterator {
+	keys := make([]string, 0, len(state))
+	for k := range state {
+		if startKey != "" && k < startKey {
+			continue
+		}
+		if endKey != "" && k >= endKey {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return &kvIterator{keys: keys, vals: state}
+}
+
+func (it *kvIterator) HasNext() bool { return it.pos < len(it.keys) }
+
+func (it *kvIterator) Next() (*queryresult.KV, error) {
+	if !it.HasNext() {
+		return nil, fmt.Errorf("iterator exhausted")
+	}
+	key := it.keys[it.pos]
+	it.pos++
+	return &queryresult.KV{Key: key, Value: it.vals[key]}, nil
+}
+
+func (it *kvIterator) Close() error { return nil }
+
+// historyIterator replays PutState/DelState records for one key, newest
+// first, matching GetHistoryForKey's documented ordering.
+type historyIterator struct {
+	records []historyRecord
+	pos     int
+}
+
+func (it *historyIterator) HasNext() bool { return it.pos < len(it.records) }
+
+func (it *historyIterator) Next() (*queryresult.KeyModification, error) {
+	if !it.HasNext() {
+		return nil, fmt.Errorf("iterator exhausted")
+	}
+	// Records are appended oldest-first; GetHistoryForKey returns newest-first.
+	r := it.records[len(it.records)-1-it.pos]
+	it.pos++
+	return &queryresult.KeyModification{
+		TxId:      r.txID,
+		Value:     r.value,
+		Timestamp: r.timestamp,
+		IsDelete:  r.isDelete,
+	}, nil
+}
+
+func (it *historyIterator) Close() error { return nil }
+
+func paginate(it shim.StateQueryIteratorInterface, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata) {
+	var all []*queryresult.KV
+	for it.HasNext() {
+		kv, err := it.Next()
+		if err != nil {
+			break
+		}
+		all = append(all, kv)
+	}
+	it.Close()
+
+	start := 0
+	if bookmark != "" {
+		for i, kv := range all {
+			if kv.Key > bookmark {
+				start = i
+				break
+			}
+		}
+	}
+
+	end := len(all)
+	if pageSize > 0 && start+int(pageSize) < end {
+		end = start + int(pageSize)
+	}
+
+	page := all[start:end]
+	nextBookmark := ""
+	if end < len(all) {
+		nextBookmark = page[len(page)-1].Key
+	}
+
+	return &staticKVIterator{kvs: page}, &peer.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(page)),
+		Bookmark:            nextBookmark,
+	}
+}
+
+// staticKVIterator replays a pre-computed, already-paginated slice of KVs.
+type staticKVIterator struct {
+	kvs []*queryresult.KV
+	pos int
+}
+
+func (it *staticKVIterator) HasNext() bool { return it.pos < len(it.kvs) }
+
+func (it *staticKVIterator) Next() (*queryresult.KV, error) {
+	if !it.HasNext() {
+		return nil, fmt.Errorf("iterator exhausted")
+	}
+	kv := it.kvs[it.pos]
+	it.pos++
+	return kv, nil
+}
+
+func (it *staticKVIterator) Close() error { return nil }
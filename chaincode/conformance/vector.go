@@ -0,0 +1,50 @@
+// Package conformance runs a corpus of JSON test vectors against the
+// chaincode's contract API in-memory, so a regression in QRAsset marshaling,
+// key formatting, or validation is caught before the chaincode is ever
+// packaged and deployed to a network.
+package conformance
+
+// Vector is one end-to-end scenario: an ordered sequence of transactions
+// submitted to a fresh chaincode instance, plus what each transaction and
+// the final world state are expected to look like.
+type Vector struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Steps       []Step `json:"steps"`
+	// ExpectedState lists key/value substrings expected in the public world
+	// state once every step has run. A step's own assertions cover its
+	// return value, error and emitted event.
+	ExpectedState []KeyValueExpectation `json:"expectedState,omitempty"`
+}
+
+// Step is a single transaction invocation and what it's expected to produce.
+type Step struct {
+	Function  string            `json:"function"`
+	Args      []string          `json:"args"`
+	Transient map[string]string `json:"transient,omitempty"`
+
+	// ExpectError, when true, asserts the invocation fails. ExpectedErrorContains
+	// (if set) additionally asserts the error message contains this substring.
+	ExpectError           bool   `json:"expectError,omitempty"`
+	ExpectedErrorContains string `json:"expectedErrorContains,omitempty"`
+
+	// ExpectedReturnContains asserts the transaction's JSON-encoded return
+	// value contains this substring. Empty means "don't check".
+	ExpectedReturnContains string `json:"expectedReturnContains,omitempty"`
+
+	// ExpectedEvent asserts the transaction emitted an event with this name
+	// whose JSON payload contains PayloadContains. Nil means "don't check".
+	ExpectedEvent *EventExpectation `json:"expectedEvent,omitempty"`
+}
+
+// EventExpectation asserts a chaincode event was emitted by a step.
+type EventExpectation struct {
+	Name            string `json:"name"`
+	PayloadContains string `json:"payloadContains,omitempty"`
+}
+
+// KeyValueExpectation asserts a world-state key's value contains a substring.
+type KeyValueExpectation struct {
+	Key           string `json:"key"`
+	ValueContains string `json:"valueContains"`
+}
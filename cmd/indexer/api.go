@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// eventLagSeconds tracks how long the indexer took to process each event
+// after it arrived from the ChaincodeEvents stream (ReadAsset round-trip,
+// Upsert, checkpoint write). client.ChaincodeEvent carries no block
+// timestamp, so this measures indexer processing lag, not true ledger age.
+var eventLagSeconds = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "indexer_event_lag_seconds",
+	Help: "Seconds the indexer took to process the most recently handled chaincode event.",
+})
+
+// newAPIServer builds the indexer's read-only HTTP API: /assets for
+// materialized queries that don't need an endorsement round-trip, plus
+// /health and /metrics for operators.
+func newAPIServer(sink Sink, ready func() bool) *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/assets", func(w http.ResponseWriter, r *http.Request) {
+		filter := AssetFilter{
+			Species:  r.URL.Query().Get("species"),
+			Producer: r.URL.Query().Get("producer"),
+			From:     r.URL.Query().Get("from"),
+			To:       r.URL.Query().Get("to"),
+		}
+		if bbox := r.URL.Query().Get("bbox"); bbox != "" {
+			parts := strings.Split(bbox, ",")
+			if len(parts) != 4 {
+				http.Error(w, "bbox must be minLat,minLng,maxLat,maxLng", http.StatusBadRequest)
+				return
+			}
+			for i, p := range parts {
+				v, err := strconv.ParseFloat(p, 64)
+				if err != nil {
+					http.Error(w, fmt.Sprintf("invalid bbox value %q: %v", p, err), http.StatusBadRequest)
+					return
+				}
+				filter.BBox[i] = v
+			}
+			filter.HasBBox = true
+		}
+
+		views, err := sink.Query(r.Context(), filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(views)
+	})
+
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if !ready() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, "not ready")
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return mux
+}
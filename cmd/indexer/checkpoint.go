@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Checkpoint records the last chaincode event this indexer processed, so a
+// restart resumes from where it left off instead of replaying the whole
+// channel.
+type Checkpoint struct {
+	BlockNumber uint64 `json:"blockNumber"`
+	TxID        string `json:"txId"`
+}
+
+func loadCheckpoint(path string) (Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Checkpoint{}, nil
+	}
+	if err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to read checkpoint %s: %w", path, err)
+	}
+
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return Checkpoint{}, fmt.Errorf("failed to unmarshal checkpoint %s: %w", path, err)
+	}
+	return cp, nil
+}
+
+func saveCheckpoint(path string, cp Checkpoint) error {
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", tmp, err)
+	}
+	return os.Rename(tmp, path)
+}
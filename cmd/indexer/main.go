@@ -0,0 +1,274 @@
+// Command indexer subscribes to the livestock chaincode's events and
+// materializes them into a queryable store, so the frontend can list/filter
+// assets over plain HTTP instead of paying an endorsement round-trip for
+// every read.
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-gateway/pkg/identity"
+	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+func envOrDefault(key, def string) string {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+var (
+	channelName     = envOrDefault("CHANNEL_NAME", "mychannel")
+	chaincodeName   = envOrDefault("CHAINCODE_NAME", "livestock")
+	mspID           = envOrDefault("MSP_ID", "Org1MSP")
+	peerEndpoint    = envOrDefault("PEER_ENDPOINT", "localhost:7051")
+	peerHostAlias   = envOrDefault("PEER_HOST_ALIAS", "peer0.org1.example.com")
+	tlsCertPath     = envOrDefault("TLS_CERT_PATH", "")
+	certPath        = envOrDefault("CERT_PATH", "")
+	keyPath         = envOrDefault("KEY_PATH", "")
+	checkpointPath  = envOrDefault("CHECKPOINT_PATH", "indexer.checkpoint")
+	listenAddr      = envOrDefault("LISTEN_ADDR", ":8081")
+	sinkKind        = envOrDefault("SINK", "couchdb")
+	couchDBURL      = envOrDefault("COUCHDB_URL", "http://localhost:5984")
+	couchDBDatabase = envOrDefault("COUCHDB_DATABASE", "livestock_index")
+	postgresURL     = envOrDefault("POSTGRES_URL", "")
+	chaincodeEvents = []string{"QRCreated", "CustodyTransferred", "QualityEventRecorded"}
+)
+
+func main() {
+	replayFromBlock := flag.Uint64("replay-from-block", 0, "rebuild the index from this block number instead of resuming from the checkpoint")
+	flag.Parse()
+
+	sink, err := newSink()
+	if err != nil {
+		log.Fatalf("Failed to build sink: %v", err)
+	}
+
+	clientConn, err := newGrpcConnection()
+	if err != nil {
+		log.Fatalf("Failed to create gRPC connection: %v", err)
+	}
+	defer clientConn.Close()
+
+	id, err := newIdentity()
+	if err != nil {
+		log.Fatalf("Failed to create identity: %v", err)
+	}
+
+	sign, err := newSign()
+	if err != nil {
+		log.Fatalf("Failed to create signer: %v", err)
+	}
+
+	gw, err := client.Connect(id, client.WithSign(sign), client.WithClientConnection(clientConn))
+	if err != nil {
+		log.Fatalf("Failed to connect to gateway: %v", err)
+	}
+	defer gw.Close()
+
+	network := gw.GetNetwork(channelName)
+	contract := network.GetContract(chaincodeName)
+
+	startBlock := *replayFromBlock
+	if startBlock == 0 {
+		cp, err := loadCheckpoint(checkpointPath)
+		if err != nil {
+			log.Fatalf("Failed to load checkpoint: %v", err)
+		}
+		startBlock = cp.BlockNumber
+	}
+
+	ready := false
+	mux := newAPIServer(sink, func() bool { return ready })
+	go func() {
+		log.Printf("Listening on %s", listenAddr)
+		if err := http.ListenAndServe(listenAddr, mux); err != nil {
+			log.Fatalf("HTTP server failed: %v", err)
+		}
+	}()
+
+	ready = true
+	runWithReconnect(network, contract, sink, startBlock)
+}
+
+// runWithReconnect subscribes to chaincode events and never returns; on a
+// stream error it backs off and resumes from the last saved checkpoint.
+func runWithReconnect(network *client.Network, contract *client.Contract, sink Sink, startBlock uint64) {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		err := consumeEvents(network, contract, sink, &startBlock)
+		if err != nil {
+			log.Printf("Event stream error, reconnecting in %s: %v", backoff, err)
+			time.Sleep(backoff)
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+		backoff = time.Second
+	}
+}
+
+// consumeEvents advances *startBlock to the block of each event it
+// checkpoints, so a later call resumes from there.
+func consumeEvents(network *client.Network, contract *client.Contract, sink Sink, startBlock *uint64) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := network.ChaincodeEvents(ctx, chaincodeName, client.WithStartBlock(*startBlock))
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to chaincode events: %w", err)
+	}
+
+	wantedEvent := make(map[string]bool, len(chaincodeEvents))
+	for _, name := range chaincodeEvents {
+		wantedEvent[name] = true
+	}
+
+	for event := range events {
+		if !wantedEvent[event.EventName] {
+			continue
+		}
+		received := time.Now()
+
+		var probe struct {
+			ProductID string `json:"productId"`
+		}
+		if err := json.Unmarshal(event.Payload, &probe); err != nil {
+			log.Printf("Skipping event %s/%s: failed to unmarshal payload: %v", event.TransactionID, event.EventName, err)
+			continue
+		}
+
+		// The event payload only ever carries a productId (QRCreated) or a
+		// custody/quality record; the species/producer/date/geo fields the
+		// HTTP API filters on live on the asset itself, so materialize the
+		// current asset rather than the thin event payload.
+		assetJSON, err := contract.EvaluateTransaction("ReadAsset", probe.ProductID)
+		if err != nil {
+			log.Printf("Skipping event %s/%s: failed to read asset %s: %v", event.TransactionID, event.EventName, probe.ProductID, err)
+			continue
+		}
+
+		var asset assetFields
+		if err := json.Unmarshal(assetJSON, &asset); err != nil {
+			log.Printf("Skipping event %s/%s: failed to unmarshal asset %s: %v", event.TransactionID, event.EventName, probe.ProductID, err)
+			continue
+		}
+
+		view := AssetView{
+			TxID:        event.TransactionID,
+			BlockNumber: event.BlockNumber,
+			ProductID:   probe.ProductID,
+			EventName:   event.EventName,
+			Species:     asset.SpeciesEn,
+			Producer:    asset.ProducerOrganizationEn,
+			HarvestDate: asset.DateOfHarvesting,
+			Latitude:    asset.ProductionLatitude,
+			Longitude:   asset.ProductionLongitude,
+			Payload:     assetJSON,
+		}
+		if err := sink.Upsert(ctx, view); err != nil {
+			return fmt.Errorf("failed to upsert event %s/%s: %w", event.TransactionID, event.EventName, err)
+		}
+
+		if err := saveCheckpoint(checkpointPath, Checkpoint{BlockNumber: event.BlockNumber, TxID: event.TransactionID}); err != nil {
+			log.Printf("Failed to save checkpoint after %s: %v", event.TransactionID, err)
+		}
+		*startBlock = event.BlockNumber
+
+		eventLagSeconds.Set(time.Since(received).Seconds())
+	}
+	return fmt.Errorf("chaincode event stream closed")
+}
+
+// assetFields pulls the subset of QRAsset's public fields the indexer needs
+// to materialize out of ReadAsset's JSON response.
+type assetFields struct {
+	SpeciesEn              string  `json:"species_en"`
+	ProducerOrganizationEn string  `json:"producer_organization_en"`
+	DateOfHarvesting       string  `json:"date_of_harvesting"`
+	ProductionLatitude     float64 `json:"production_latitude"`
+	ProductionLongitude    float64 `json:"production_longitude"`
+}
+
+func newSink() (Sink, error) {
+	switch sinkKind {
+	case "couchdb":
+		return NewCouchDBSink(couchDBURL, couchDBDatabase), nil
+	case "postgres":
+		db, err := sql.Open("postgres", postgresURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open postgres connection: %w", err)
+		}
+		return NewPostgresSink(db), nil
+	default:
+		return nil, fmt.Errorf("unknown SINK %q, want couchdb or postgres", sinkKind)
+	}
+}
+
+func newGrpcConnection() (*grpc.ClientConn, error) {
+	certPEM, err := os.ReadFile(tlsCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read TLS cert at %s: %w", tlsCertPath, err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(certPEM) {
+		return nil, fmt.Errorf("failed to append TLS cert from %s", tlsCertPath)
+	}
+	creds := credentials.NewClientTLSFromCert(certPool, peerHostAlias)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, peerEndpoint, grpc.WithTransportCredentials(creds), grpc.WithBlock())
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial %s: %w", peerEndpoint, err)
+	}
+	return conn, nil
+}
+
+func newIdentity() (*identity.X509Identity, error) {
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate %s: %w", certPath, err)
+	}
+
+	cert, err := identity.CertificateFromPEM(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate PEM: %w", err)
+	}
+
+	return identity.NewX509Identity(mspID, cert)
+}
+
+func newSign() (identity.Sign, error) {
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key %s: %w", keyPath, err)
+	}
+
+	privateKey, err := identity.PrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return identity.NewPrivateKeySign(privateKey)
+}
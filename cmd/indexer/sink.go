@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// AssetView is the materialized, query-friendly shape of an asset plus the
+// provenance fields the indexer adds (which tx/block produced it). Species,
+// Producer, HarvestDate, Latitude and Longitude are pulled out of the full
+// asset (fetched via ReadAsset when the event arrives) so the HTTP API can
+// filter on them directly instead of reaching into an opaque payload blob.
+type AssetView struct {
+	TxID        string          `json:"txId"`
+	BlockNumber uint64          `json:"blockNumber"`
+	ProductID   string          `json:"productId"`
+	EventName   string          `json:"eventName"`
+	Species     string          `json:"species"`
+	Producer    string          `json:"producer"`
+	HarvestDate string          `json:"harvestDate"`
+	Latitude    float64         `json:"latitude"`
+	Longitude   float64         `json:"longitude"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Sink materializes chaincode events into a queryable store. Upsert must be
+// idempotent on TxID so at-least-once delivery from ChaincodeEvents doesn't
+// create duplicate rows/documents on redelivery.
+type Sink interface {
+	Upsert(ctx context.Context, view AssetView) error
+	Query(ctx context.Context, filter AssetFilter) ([]AssetView, error)
+}
+
+// AssetFilter mirrors the query parameters the HTTP API accepts. BBox is
+// [minLat, minLng, maxLat, maxLng] and only applies when HasBBox is set.
+type AssetFilter struct {
+	Species  string
+	Producer string
+	From     string
+	To       string
+	BBox     [4]float64
+	HasBBox  bool
+}
+
+// CouchDBSink writes materialized views as documents in a CouchDB database,
+// keyed by txId so repeated delivery of the same event is a no-op update.
+type CouchDBSink struct {
+	baseURL  string
+	database string
+	client   *http.Client
+}
+
+// NewCouchDBSink returns a Sink backed by the CouchDB database at
+// baseURL/database (e.g. "http://localhost:5984", "livestock_index").
+func NewCouchDBSink(baseURL, database string) *CouchDBSink {
+	return &CouchDBSink{baseURL: baseURL, database: database, client: http.DefaultClient}
+}
+
+func (c *CouchDBSink) Upsert(ctx context.Context, view AssetView) error {
+	body, err := json.Marshal(view)
+	if err != nil {
+		return fmt.Errorf("failed to marshal asset view: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/%s", c.baseURL, c.database, view.TxID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build CouchDB request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upsert %s into CouchDB: %w", view.TxID, err)
+	}
+	defer resp.Body.Close()
+
+	// 201 Created and 409 Conflict (already indexed, idempotent) are both fine.
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("CouchDB upsert of %s failed with status %s", view.TxID, resp.Status)
+	}
+	return nil
+}
+
+func (c *CouchDBSink) Query(ctx context.Context, filter AssetFilter) ([]AssetView, error) {
+	selector := map[string]interface{}{}
+	if filter.Species != "" {
+		selector["species"] = filter.Species
+	}
+	if filter.Producer != "" {
+		selector["producer"] = filter.Producer
+	}
+	if filter.From != "" || filter.To != "" {
+		dateRange := map[string]interface{}{}
+		if filter.From != "" {
+			dateRange["$gte"] = filter.From
+		}
+		if filter.To != "" {
+			dateRange["$lte"] = filter.To
+		}
+		selector["harvestDate"] = dateRange
+	}
+	if filter.HasBBox {
+		selector["latitude"] = map[string]interface{}{"$gte": filter.BBox[0], "$lte": filter.BBox[2]}
+		selector["longitude"] = map[string]interface{}{"$gte": filter.BBox[1], "$lte": filter.BBox[3]}
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"selector": selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Mango selector: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/_find", c.baseURL, c.database)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CouchDB query: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CouchDB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Docs []AssetView `json:"docs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode CouchDB response: %w", err)
+	}
+	return result.Docs, nil
+}
+
+// PostgresSink writes materialized views into a Postgres table, upserting on
+// the tx_id primary key so redelivered events don't duplicate rows.
+type PostgresSink struct {
+	db *sql.DB
+}
+
+// NewPostgresSink wraps an already-open *sql.DB. The caller owns the
+// connection lifecycle.
+func NewPostgresSink(db *sql.DB) *PostgresSink {
+	return &PostgresSink{db: db}
+}
+
+func (p *PostgresSink) Upsert(ctx context.Context, view AssetView) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO asset_views (tx_id, block_number, product_id, event_name, species, producer, harvest_date, latitude, longitude, payload)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (tx_id) DO UPDATE SET
+			block_number = EXCLUDED.block_number,
+			product_id = EXCLUDED.product_id,
+			event_name = EXCLUDED.event_name,
+			species = EXCLUDED.species,
+			producer = EXCLUDED.producer,
+			harvest_date = EXCLUDED.harvest_date,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			payload = EXCLUDED.payload
+	`, view.TxID, view.BlockNumber, view.ProductID, view.EventName, view.Species, view.Producer, view.HarvestDate, view.Latitude, view.Longitude, []byte(view.Payload))
+	if err != nil {
+		return fmt.Errorf("failed to upsert %s into postgres: %w", view.TxID, err)
+	}
+	return nil
+}
+
+func (p *PostgresSink) Query(ctx context.Context, filter AssetFilter) ([]AssetView, error) {
+	query := `SELECT tx_id, block_number, product_id, event_name, species, producer, harvest_date, latitude, longitude, payload FROM asset_views WHERE 1=1`
+	var args []interface{}
+
+	if filter.Species != "" {
+		args = append(args, filter.Species)
+		query += fmt.Sprintf(" AND species = $%d", len(args))
+	}
+	if filter.Producer != "" {
+		args = append(args, filter.Producer)
+		query += fmt.Sprintf(" AND producer = $%d", len(args))
+	}
+	if filter.From != "" {
+		args = append(args, filter.From)
+		query += fmt.Sprintf(" AND harvest_date >= $%d", len(args))
+	}
+	if filter.To != "" {
+		args = append(args, filter.To)
+		query += fmt.Sprintf(" AND harvest_date <= $%d", len(args))
+	}
+	if filter.HasBBox {
+		args = append(args, filter.BBox[0], filter.BBox[2])
+		query += fmt.Sprintf(" AND latitude BETWEEN $%d AND $%d", len(args)-1, len(args))
+		args = append(args, filter.BBox[1], filter.BBox[3])
+		query += fmt.Sprintf(" AND longitude BETWEEN $%d AND $%d", len(args)-1, len(args))
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query postgres: %w", err)
+	}
+	defer rows.Close()
+
+	var views []AssetView
+	for rows.Next() {
+		var v AssetView
+		var payload []byte
+		if err := rows.Scan(&v.TxID, &v.BlockNumber, &v.ProductID, &v.EventName, &v.Species, &v.Producer, &v.HarvestDate, &v.Latitude, &v.Longitude, &payload); err != nil {
+			return nil, fmt.Errorf("failed to scan asset view row: %w", err)
+		}
+		v.Payload = payload
+		views = append(views, v)
+	}
+	return views, rows.Err()
+}